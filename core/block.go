@@ -0,0 +1,377 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/nebulasio/go-nebulas/core/state"
+	"github.com/nebulasio/go-nebulas/util"
+	"github.com/nebulasio/go-nebulas/util/byteutils"
+)
+
+// EIP-1559 style base fee parameters. The base fee moves in proportion to
+// how far the parent block's gas used was over or under its gas target,
+// capped at 1/MaxBaseFeeChangeDenominator of the parent's base fee per
+// block so the fee cannot swing further than 12.5% between consecutive
+// blocks.
+const (
+	// ElasticityMultiplier is the ratio between BlockGasLimit and the gas
+	// target used by the base fee update rule.
+	ElasticityMultiplier = 2
+
+	// MaxBaseFeeChangeDenominator bounds the per-block base fee delta to
+	// 1/8th (12.5%) of the parent base fee.
+	MaxBaseFeeChangeDenominator = 8
+
+	// InitialBaseFee seeds the base fee of the first block that enables the
+	// dynamic fee market.
+	InitialBaseFee = 1000000000
+)
+
+// Blob gas market parameters, modeled as a second, independent gas market
+// alongside the EIP-1559 style execution gas market above: every blob a
+// TxTypeBlob transaction carries consumes a fixed amount of blob gas, and
+// the blob base fee moves exponentially with how far the chain's running
+// "excess blob gas" sits above the per-block target.
+const (
+	// BlobGasPerBlob is the fixed amount of blob gas a single blob
+	// consumes, regardless of its actual (padded) size.
+	BlobGasPerBlob = 1 << 17
+
+	// MaxBlobsPerBlock caps how many blobs a single block may include.
+	MaxBlobsPerBlock = 6
+
+	// TargetBlobsPerBlock is the long-run equilibrium blob count; the
+	// excess blob gas accumulator rises when a block carries more blobs
+	// than this and falls (floored at zero) when it carries fewer.
+	TargetBlobsPerBlock = MaxBlobsPerBlock / 2
+
+	// TargetBlobGasPerBlock is TargetBlobsPerBlock expressed in blob gas.
+	TargetBlobGasPerBlock = TargetBlobsPerBlock * BlobGasPerBlob
+
+	// MinBlobBaseFee is the floor the blob base fee never drops below.
+	MinBlobBaseFee = 1
+
+	// BlobBaseFeeUpdateFraction controls how quickly the blob base fee
+	// reacts to excess blob gas: smaller values make it more sensitive.
+	BlobBaseFeeUpdateFraction = 3338477
+)
+
+// BlockHeader carries the block metadata needed to execute and validate the
+// transactions in a block.
+type BlockHeader struct {
+	hash       byteutils.Hash
+	parentHash byteutils.Hash
+	coinbase   *Address
+	timestamp  int64
+	chainID    uint32
+
+	// gasUsed is the total gas consumed so far by this block's own
+	// transactions; CalcNextBaseFee derives the next block's base fee from
+	// it once this block is a parent.
+	gasUsed  *util.Uint128
+	gasLimit *util.Uint128
+
+	// baseFee is the EIP-1559 style per-block base fee: every transaction in
+	// this block burns at least baseFee per unit of gas it consumes.
+	baseFee *util.Uint128
+
+	// blobGasUsed/blobBaseFee/excessBlobGas track the independent blob gas
+	// market introduced alongside TxTypeBlob transactions. excessBlobGas is
+	// the running accumulator CalcNextExcessBlobGas/CalcNextBlobBaseFee are
+	// derived from; blobGasUsed is reset every block to the blob gas spent
+	// by that block's own transactions.
+	blobGasUsed   *util.Uint128
+	blobBaseFee   *util.Uint128
+	excessBlobGas *util.Uint128
+}
+
+// Block represents one block of the Nebulas chain together with the world
+// state resulting from applying its transactions.
+type Block struct {
+	header     *BlockHeader
+	worldState state.WorldState
+}
+
+// BaseFee returns the per-gas amount burned (not credited to the coinbase)
+// by every transaction executed in this block.
+func (block *Block) BaseFee() *util.Uint128 {
+	if block.header.baseFee == nil {
+		return util.NewUint128()
+	}
+	return block.header.baseFee
+}
+
+// BlobBaseFee returns the per-blob-gas amount burned by blob transactions
+// executed in this block.
+func (block *Block) BlobBaseFee() *util.Uint128 {
+	if block.header.blobBaseFee == nil {
+		return util.NewUint128()
+	}
+	return block.header.blobBaseFee
+}
+
+// GasLimit returns the block's total gas limit.
+func (block *Block) GasLimit() *util.Uint128 {
+	return block.header.gasLimit
+}
+
+// GasUsed returns the total gas consumed by this block's transactions so
+// far. It is what the block that extends this one derives its base fee
+// from, via CalcNextBaseFee.
+func (block *Block) GasUsed() *util.Uint128 {
+	if block.header.gasUsed == nil {
+		return util.NewUint128()
+	}
+	return block.header.gasUsed
+}
+
+// addGasUsed records that a transaction consumed gas of execution gas in
+// this block.
+func (block *Block) addGasUsed(gas *util.Uint128) error {
+	used, err := block.GasUsed().Add(gas)
+	if err != nil {
+		return err
+	}
+	block.header.gasUsed = used
+	return nil
+}
+
+// BlobGasUsed returns the total blob gas consumed by this block's
+// transactions so far.
+func (block *Block) BlobGasUsed() *util.Uint128 {
+	if block.header.blobGasUsed == nil {
+		return util.NewUint128()
+	}
+	return block.header.blobGasUsed
+}
+
+// addBlobGasUsed records that a TxTypeBlob transaction consumed blobGas of
+// blob gas in this block.
+func (block *Block) addBlobGasUsed(blobGas *util.Uint128) error {
+	used, err := block.BlobGasUsed().Add(blobGas)
+	if err != nil {
+		return err
+	}
+	block.header.blobGasUsed = used
+	return nil
+}
+
+// begin opens a snapshot of the account state so execution can be tried and
+// discarded via rollback without mutating the committed state.
+func (block *Block) begin() {
+	block.worldState.Begin()
+}
+
+// rollback discards any state changes made since the matching begin().
+func (block *Block) rollback() {
+	block.worldState.RollBack()
+}
+
+// NextBlockHeader builds the header of the block that extends parent: its
+// gasLimit is fixed by the caller, while baseFee, excessBlobGas and
+// blobBaseFee are derived from parent via CalcNextBaseFee,
+// CalcNextExcessBlobGas and CalcNextBlobBaseFee - the production entry
+// point those formulas feed into whenever a new block is built.
+func NextBlockHeader(parent *Block, coinbase *Address, chainID uint32, timestamp int64, gasLimit *util.Uint128) (*BlockHeader, error) {
+	baseFee, err := CalcNextBaseFee(parent.BaseFee(), parent.GasUsed(), parent.header.gasLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	excessBlobGas, err := CalcNextExcessBlobGas(parent.header.excessBlobGas, parent.BlobGasUsed())
+	if err != nil {
+		return nil, err
+	}
+	blobBaseFee, err := CalcNextBlobBaseFee(excessBlobGas)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockHeader{
+		parentHash:    parent.header.hash,
+		coinbase:      coinbase,
+		timestamp:     timestamp,
+		chainID:       chainID,
+		gasLimit:      gasLimit,
+		baseFee:       baseFee,
+		excessBlobGas: excessBlobGas,
+		blobBaseFee:   blobBaseFee,
+	}, nil
+}
+
+// FetchEvents returns the events recorded against a transaction hash during
+// its execution in this block.
+func (block *Block) FetchEvents(txHash byteutils.Hash) ([]*state.Event, error) {
+	return block.worldState.FetchEvents(txHash)
+}
+
+// CalcNextBaseFee derives the base fee of the block that extends parent,
+// following the target/elastic-limit rule: if the parent used more gas than
+// its target (gasLimit / ElasticityMultiplier), the base fee rises
+// proportionally to the overshoot; if it used less, the base fee falls
+// proportionally to the undershoot. The per-block move is capped at
+// 1/MaxBaseFeeChangeDenominator (12.5%) of the parent base fee in either
+// direction.
+func CalcNextBaseFee(parentBaseFee, parentGasUsed, parentGasLimit *util.Uint128) (*util.Uint128, error) {
+	if parentBaseFee == nil {
+		return nil, ErrInvalidBaseFee
+	}
+	if parentGasLimit == nil || parentGasLimit.Cmp(util.NewUint128()) == 0 {
+		return parentBaseFee, nil
+	}
+
+	gasTarget, err := parentGasLimit.Div(mustUint128(ElasticityMultiplier))
+	if err != nil {
+		return nil, err
+	}
+	if gasTarget.Cmp(util.NewUint128()) == 0 {
+		return parentBaseFee, nil
+	}
+
+	switch parentGasUsed.Cmp(gasTarget) {
+	case 0:
+		return parentBaseFee, nil
+	case 1:
+		// overshoot: baseFee += baseFee * (used - target) / target / denominator, floor 1
+		gasUsedDelta, err := parentGasUsed.Sub(gasTarget)
+		if err != nil {
+			return nil, err
+		}
+		if gasUsedDelta.Cmp(gasTarget) > 0 {
+			gasUsedDelta = gasTarget
+		}
+		delta, err := calcBaseFeeDelta(parentBaseFee, gasUsedDelta, gasTarget)
+		if err != nil {
+			return nil, err
+		}
+		if delta.Cmp(util.NewUint128()) == 0 {
+			delta = mustUint128(1)
+		}
+		return parentBaseFee.Add(delta)
+	default:
+		// undershoot: baseFee -= baseFee * (target - used) / target / denominator
+		gasUsedDelta, err := gasTarget.Sub(parentGasUsed)
+		if err != nil {
+			return nil, err
+		}
+		delta, err := calcBaseFeeDelta(parentBaseFee, gasUsedDelta, gasTarget)
+		if err != nil {
+			return nil, err
+		}
+		next, err := parentBaseFee.Sub(delta)
+		if err != nil || next.Cmp(util.NewUint128()) < 0 {
+			return util.NewUint128(), nil
+		}
+		return next, nil
+	}
+}
+
+func calcBaseFeeDelta(baseFee, gasUsedDelta, gasTarget *util.Uint128) (*util.Uint128, error) {
+	num, err := baseFee.Mul(gasUsedDelta)
+	if err != nil {
+		return nil, err
+	}
+	num, err = num.Div(gasTarget)
+	if err != nil {
+		return nil, err
+	}
+	return num.Div(mustUint128(MaxBaseFeeChangeDenominator))
+}
+
+// CalcNextExcessBlobGas derives the excess blob gas accumulator of the
+// block that extends parent: it rises by however much the parent block's
+// blobGasUsed exceeded TargetBlobGasPerBlock, and falls by the same amount
+// it undershot it, floored at zero.
+func CalcNextExcessBlobGas(parentExcessBlobGas, parentBlobGasUsed *util.Uint128) (*util.Uint128, error) {
+	if parentExcessBlobGas == nil {
+		parentExcessBlobGas = util.NewUint128()
+	}
+	if parentBlobGasUsed == nil {
+		parentBlobGasUsed = util.NewUint128()
+	}
+
+	total, err := parentExcessBlobGas.Add(parentBlobGasUsed)
+	if err != nil {
+		return nil, err
+	}
+	target := mustUint128(TargetBlobGasPerBlock)
+	if total.Cmp(target) < 0 {
+		return util.NewUint128(), nil
+	}
+	return total.Sub(target)
+}
+
+// CalcNextBlobBaseFee derives the blob base fee from the running excess
+// blob gas accumulator, following the same exponential rule as EIP-4844's
+// fake_exponential: the fee grows by a factor of about e (~2.718) every
+// BlobBaseFeeUpdateFraction of excess blob gas accumulated, truncated to an
+// integer.
+func CalcNextBlobBaseFee(excessBlobGas *util.Uint128) (*util.Uint128, error) {
+	if excessBlobGas == nil || excessBlobGas.Cmp(util.NewUint128()) == 0 {
+		return mustUint128(MinBlobBaseFee), nil
+	}
+
+	// fakeExponential(MinBlobBaseFee, excessBlobGas, BlobBaseFeeUpdateFraction),
+	// approximated via its Taylor expansion: f(x) = MinBlobBaseFee *
+	// sum(x^n / (fraction^n * n!)), truncated once terms become negligible.
+	num := mustUint128(MinBlobBaseFee)
+	accum, err := num.Mul(mustUint128(BlobBaseFeeUpdateFraction))
+	if err != nil {
+		return nil, err
+	}
+	fraction := mustUint128(BlobBaseFeeUpdateFraction)
+
+	output := util.NewUint128()
+	for i := uint64(1); accum.Cmp(util.NewUint128()) > 0; i++ {
+		output, err = output.Add(accum)
+		if err != nil {
+			return nil, err
+		}
+		accum, err = accum.Mul(excessBlobGas)
+		if err != nil {
+			return nil, err
+		}
+		accum, err = accum.Div(fraction)
+		if err != nil {
+			return nil, err
+		}
+		divisor := mustUint128(i)
+		accum, err = accum.Div(divisor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := output.Div(fraction)
+	if err != nil {
+		return nil, err
+	}
+	if result.Cmp(mustUint128(MinBlobBaseFee)) < 0 {
+		return mustUint128(MinBlobBaseFee), nil
+	}
+	return result, nil
+}
+
+func mustUint128(v uint64) *util.Uint128 {
+	n, err := util.NewUint128FromInt(int64(v))
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/nebulasio/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcNextBaseFee(t *testing.T) {
+	gasLimit := util.NewUint128FromUint(20000000)
+	target := util.NewUint128FromUint(10000000) // gasLimit / ElasticityMultiplier
+	baseFee := util.NewUint128FromUint(1000000000)
+
+	t.Run("gas used at target leaves base fee unchanged", func(t *testing.T) {
+		next, err := CalcNextBaseFee(baseFee, target, gasLimit)
+		assert.Nil(t, err)
+		assert.Equal(t, baseFee.String(), next.String())
+	})
+
+	t.Run("full block overshoot raises base fee by the 12.5% cap", func(t *testing.T) {
+		next, err := CalcNextBaseFee(baseFee, gasLimit, gasLimit)
+		assert.Nil(t, err)
+		want, err := baseFee.Add(util.NewUint128FromUint(125000000)) // baseFee/8
+		assert.Nil(t, err)
+		assert.Equal(t, want.String(), next.String())
+	})
+
+	t.Run("empty block undershoot lowers base fee by the 12.5% cap", func(t *testing.T) {
+		next, err := CalcNextBaseFee(baseFee, util.NewUint128(), gasLimit)
+		assert.Nil(t, err)
+		want, err := baseFee.Sub(util.NewUint128FromUint(125000000)) // baseFee/8
+		assert.Nil(t, err)
+		assert.Equal(t, want.String(), next.String())
+	})
+
+	t.Run("partial overshoot moves base fee proportionally, short of the cap", func(t *testing.T) {
+		gasUsed, err := target.Add(util.NewUint128FromUint(1000000)) // 10% over target
+		assert.Nil(t, err)
+		next, err := CalcNextBaseFee(baseFee, gasUsed, gasLimit)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, next.Cmp(baseFee))
+		maxNext, err := baseFee.Add(util.NewUint128FromUint(125000000))
+		assert.Nil(t, err)
+		assert.Equal(t, -1, next.Cmp(maxNext))
+	})
+
+	t.Run("zero gasLimit leaves base fee unchanged", func(t *testing.T) {
+		next, err := CalcNextBaseFee(baseFee, util.NewUint128(), util.NewUint128())
+		assert.Nil(t, err)
+		assert.Equal(t, baseFee.String(), next.String())
+	})
+
+	t.Run("nil parent base fee is rejected", func(t *testing.T) {
+		next, err := CalcNextBaseFee(nil, target, gasLimit)
+		assert.Equal(t, ErrInvalidBaseFee, err)
+		assert.Nil(t, next)
+	})
+}
+
+func TestBlockGasUsed(t *testing.T) {
+	parent := testNeb(t).chain.tailBlock
+
+	assert.Equal(t, uint64(0), parent.GasUsed().Uint64())
+	assert.Nil(t, parent.addGasUsed(util.NewUint128FromUint(21000)))
+	assert.Equal(t, uint64(21000), parent.GasUsed().Uint64())
+
+	next, err := CalcNextBaseFee(parent.BaseFee(), parent.GasUsed(), parent.GasLimit())
+	assert.Nil(t, err)
+	assert.NotNil(t, next)
+}
+
+func TestCalcNextExcessBlobGas(t *testing.T) {
+	target := mustUint128(TargetBlobGasPerBlock)
+
+	t.Run("usage above target raises the accumulator by the excess", func(t *testing.T) {
+		parentUsed, err := target.Add(mustUint128(BlobGasPerBlob))
+		assert.Nil(t, err)
+		next, err := CalcNextExcessBlobGas(util.NewUint128(), parentUsed)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(BlobGasPerBlob), next.Uint64())
+	})
+
+	t.Run("usage below target floors the accumulator at zero", func(t *testing.T) {
+		next, err := CalcNextExcessBlobGas(util.NewUint128(), util.NewUint128())
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(0), next.Uint64())
+	})
+
+	t.Run("nil parent fields are treated as zero", func(t *testing.T) {
+		next, err := CalcNextExcessBlobGas(nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(0), next.Uint64())
+	})
+}
+
+func TestCalcNextBlobBaseFee(t *testing.T) {
+	t.Run("no excess blob gas is the floor", func(t *testing.T) {
+		fee, err := CalcNextBlobBaseFee(util.NewUint128())
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(MinBlobBaseFee), fee.Uint64())
+
+		fee, err = CalcNextBlobBaseFee(nil)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(MinBlobBaseFee), fee.Uint64())
+	})
+
+	t.Run("blob base fee rises monotonically with excess blob gas", func(t *testing.T) {
+		low, err := CalcNextBlobBaseFee(mustUint128(TargetBlobGasPerBlock))
+		assert.Nil(t, err)
+		high, err := CalcNextBlobBaseFee(mustUint128(TargetBlobGasPerBlock * 10))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, high.Cmp(low))
+	})
+
+	t.Run("excess blob gas equal to the update fraction multiplies the fee by about e", func(t *testing.T) {
+		fee, err := CalcNextBlobBaseFee(mustUint128(BlobBaseFeeUpdateFraction))
+		assert.Nil(t, err)
+		// fakeExponential(1, fraction, fraction) ~= e^1 ~= 2.718, truncated to 2
+		assert.Equal(t, 0, fee.Cmp(mustUint128(2)))
+		assert.Equal(t, -1, fee.Cmp(mustUint128(4)))
+	})
+}
+
+func TestNextBlockHeader(t *testing.T) {
+	parent := testNeb(t).chain.tailBlock
+
+	t.Run("base fee and blob gas market fields are derived from parent", func(t *testing.T) {
+		assert.Nil(t, parent.addGasUsed(parent.GasLimit()))
+		assert.Nil(t, parent.addBlobGasUsed(mustUint128(TargetBlobGasPerBlock+BlobGasPerBlob)))
+
+		coinbase := mockAddress()
+		header, err := NextBlockHeader(parent, coinbase, parent.header.chainID, 0, parent.GasLimit())
+		assert.Nil(t, err)
+
+		wantBaseFee, err := CalcNextBaseFee(parent.BaseFee(), parent.GasUsed(), parent.GasLimit())
+		assert.Nil(t, err)
+		assert.Equal(t, wantBaseFee.String(), header.baseFee.String())
+
+		wantExcessBlobGas, err := CalcNextExcessBlobGas(parent.header.excessBlobGas, parent.BlobGasUsed())
+		assert.Nil(t, err)
+		assert.Equal(t, wantExcessBlobGas.String(), header.excessBlobGas.String())
+
+		wantBlobBaseFee, err := CalcNextBlobBaseFee(wantExcessBlobGas)
+		assert.Nil(t, err)
+		assert.Equal(t, wantBlobBaseFee.String(), header.blobBaseFee.String())
+
+		assert.Equal(t, coinbase, header.coinbase)
+		assert.Equal(t, parent.header.hash, header.parentHash)
+	})
+}
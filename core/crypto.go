@@ -0,0 +1,53 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/nebulasio/go-nebulas/crypto"
+	"github.com/nebulasio/go-nebulas/crypto/keystore"
+)
+
+// RecoverSignerFromSignature recovers the address that produced sign over
+// data, using the given signature algorithm.
+func RecoverSignerFromSignature(alg keystore.Algorithm, data []byte, sign []byte) (*Address, error) {
+	if len(data) == 0 || len(sign) == 0 {
+		return nil, ErrInvalidArgument
+	}
+
+	signature, err := crypto.NewSignature(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := signature.RecoverPublic(data, sign)
+	if err != nil {
+		return nil, err
+	}
+
+	pubdata, err := pub.Encoded()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := NewAddressFromPublicKey(pubdata)
+	if err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
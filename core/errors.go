@@ -0,0 +1,61 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import "errors"
+
+// transaction related errors
+var (
+	ErrInvalidTxPayloadType   = errors.New("invalid transaction data payload type")
+	ErrInvalidChainID         = errors.New("invalid transaction chainID")
+	ErrInsufficientBalance    = errors.New("insufficient balance")
+	ErrInvalidSignature       = errors.New("invalid transaction signature")
+	ErrInvalidTransactionHash = errors.New("invalid transaction hash")
+	ErrInvalidGasPrice        = errors.New("invalid gas price, should be in (0, 10^12]")
+	ErrInvalidGasLimit        = errors.New("invalid gas limit, should be in (0, 5*10^10]")
+	ErrOutOfGasLimit          = errors.New("out of gas limit")
+	ErrZeroGasPrice           = errors.New("gas price is zero")
+	ErrZeroGasLimit           = errors.New("gas limit is zero")
+
+	// EIP-1559 style dynamic fee transaction errors.
+	ErrInvalidTxType         = errors.New("invalid transaction type")
+	ErrFeeCapLessThanBaseFee = errors.New("max fee per gas less than block base fee")
+	ErrTipAboveFeeCap        = errors.New("max priority fee per gas higher than max fee per gas")
+	ErrInvalidBaseFee        = errors.New("invalid block base fee")
+
+	// EIP-4844 style blob-carrying transaction errors.
+	ErrInvalidBlobVersionedHash = errors.New("invalid blob versioned hash")
+	ErrNoBlobs                  = errors.New("blob transaction missing blob hashes")
+	ErrTooManyBlobs             = errors.New("blob transaction exceeds per-block blob limit")
+	ErrBlobFeeCapTooLow         = errors.New("max fee per blob gas below block blob base fee")
+
+	// typed signer errors.
+	ErrInvalidSigParts = errors.New("invalid signature parts")
+
+	// access-list transaction errors.
+	ErrInvalidAccessList = errors.New("invalid access list payload")
+)
+
+// address related errors
+var (
+	ErrInvalidArgument        = errors.New("invalid argument")
+	ErrInvalidAddressFormat   = errors.New("invalid address format")
+	ErrInvalidAddressType     = errors.New("invalid address type")
+	ErrInvalidAddressChecksum = errors.New("invalid address checksum")
+)
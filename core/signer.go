@@ -0,0 +1,247 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/nebulasio/go-nebulas/crypto/hash"
+	"github.com/nebulasio/go-nebulas/crypto/keystore"
+	"github.com/nebulasio/go-nebulas/util/byteutils"
+)
+
+// Signer abstracts how a transaction is hashed and how its sender is
+// recovered from its signature. Routing Sign and VerifyIntegrity through a
+// Signer - instead of hardcoding one hash/recover scheme inline - is what
+// lets a future typed envelope or hard fork change the signed preimage by
+// adding a new Signer, rather than every caller having to learn a new
+// branch.
+type Signer interface {
+	// Hash returns the preimage tx must be signed over.
+	Hash(tx *Transaction) (byteutils.Hash, error)
+
+	// Sender recovers and returns the address that produced tx's
+	// signature, after verifying that signature was made over Hash(tx).
+	Sender(tx *Transaction) (*Address, error)
+
+	// SigParts splits a recoverable secp256k1 signature into its r, s and
+	// v components.
+	SigParts(sig []byte) (r, s, v []byte, err error)
+}
+
+// recoverableSigLength is the length in bytes of a recoverable secp256k1
+// signature: a 32-byte r, a 32-byte s, and a 1-byte recovery id (v).
+const recoverableSigLength = 65
+
+// chainIDSigner verifies every TxType except TxTypeLegacy. Those
+// transactions sign over a preimage that binds tx.chainID (see its own
+// Hash method below), so Sender rejects a transaction signed for one chain
+// before a verifier on another chain ever checks its signature - replaying
+// it across the Nebulas main chain, testnet and a private chain all fail
+// the same way.
+type chainIDSigner struct {
+	chainID uint32
+}
+
+// legacySigner verifies TxTypeLegacy transactions. Their preimage predates
+// chain-ID binding and never included it (see its own Hash method below),
+// so a legacy signature by itself doesn't prove which chain it was made
+// for; Sender still refuses a chainID mismatch against the transaction's
+// own field, so replay across chains is rejected the same way it is for
+// every other TxType, just not by the signature's own bytes.
+type legacySigner struct {
+	chainID uint32
+}
+
+// latestSigner dispatches to legacySigner or chainIDSigner depending on
+// tx.TxType - the single place a new TxType's signing scheme gets wired in,
+// instead of VerifyIntegrity picking one inline.
+type latestSigner struct {
+	chainID uint32
+}
+
+// LatestSignerForChainID returns the Signer new transactions on chainID
+// should be signed and verified with.
+func LatestSignerForChainID(chainID uint32) Signer {
+	return &latestSigner{chainID: chainID}
+}
+
+func (s *latestSigner) signerFor(tx *Transaction) Signer {
+	if tx.txType == TxTypeLegacy {
+		return &legacySigner{chainID: s.chainID}
+	}
+	return &chainIDSigner{chainID: s.chainID}
+}
+
+func (s *latestSigner) Hash(tx *Transaction) (byteutils.Hash, error) {
+	return s.signerFor(tx).Hash(tx)
+}
+
+func (s *latestSigner) Sender(tx *Transaction) (*Address, error) {
+	return s.signerFor(tx).Sender(tx)
+}
+
+func (s *latestSigner) SigParts(sig []byte) (r, sPart, v []byte, err error) {
+	if len(sig) != recoverableSigLength {
+		return nil, nil, nil, ErrInvalidSigParts
+	}
+	return sig[0:32], sig[32:64], sig[64:65], nil
+}
+
+// Hash assembles the preimage for every TxType except TxTypeLegacy. Unlike
+// the legacy preimage, this one binds tx.chainID - see chainIDSigner's own
+// doc comment - and, from TxTypeDynamicFee on, signs the fee cap fields
+// rather than a single gasPrice.
+func (s *chainIDSigner) Hash(tx *Transaction) (byteutils.Hash, error) {
+	value, err := tx.value.ToFixedSizeBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	maxFeePerGas, err := tx.maxFeePerGas.ToFixedSizeBytes()
+	if err != nil {
+		return nil, err
+	}
+	maxPriorityFeePerGas, err := tx.maxPriorityFeePerGas.ToFixedSizeBytes()
+	if err != nil {
+		return nil, err
+	}
+	gasLimit, err := tx.gasLimit.ToFixedSizeBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	parts := [][]byte{
+		tx.from.address,
+		tx.to.address,
+		value,
+		byteutils.FromUint64(tx.nonce),
+		byteutils.FromInt64(tx.timestamp),
+		[]byte(tx.data.Type),
+		tx.data.Payload,
+		{tx.txType},
+		byteutils.FromUint32(tx.chainID),
+		maxFeePerGas,
+		maxPriorityFeePerGas,
+		gasLimit,
+	}
+
+	// Binding the versioned hashes and blob fee cap into the signed
+	// preimage is what makes the sidecar's KZG commitments tamper-proof:
+	// the sidecar itself never touches consensus, only its hashes do.
+	if tx.txType == TxTypeBlob {
+		maxFeePerBlobGas, err := tx.maxFeePerBlobGas.ToFixedSizeBytes()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, maxFeePerBlobGas)
+		for _, h := range tx.blobVersionedHashes {
+			parts = append(parts, h)
+		}
+	}
+
+	return hash.Sha3256(parts...), nil
+}
+
+func (s *chainIDSigner) Sender(tx *Transaction) (*Address, error) {
+	if tx.chainID != s.chainID {
+		return nil, ErrInvalidChainID
+	}
+
+	wantHash, err := s.Hash(tx)
+	if err != nil {
+		return nil, err
+	}
+	if !tx.hash.Equals(wantHash) {
+		return nil, ErrInvalidTransactionHash
+	}
+
+	return RecoverSignerFromSignature(keystore.Algorithm(tx.alg), tx.hash, tx.sign)
+}
+
+func (s *chainIDSigner) SigParts(sig []byte) (r, sPart, v []byte, err error) {
+	if len(sig) != recoverableSigLength {
+		return nil, nil, nil, ErrInvalidSigParts
+	}
+	return sig[0:32], sig[32:64], sig[64:65], nil
+}
+
+// Hash assembles the preimage for TxTypeLegacy transactions. It predates
+// chain-ID binding, so - unlike chainIDSigner.Hash - it omits tx.chainID and
+// signs a single gasPrice rather than the fee cap fields; replay protection
+// for it rests on the chainID field check in Sender rather than on the
+// signature itself.
+func (s *legacySigner) Hash(tx *Transaction) (byteutils.Hash, error) {
+	value, err := tx.value.ToFixedSizeBytes()
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := tx.gasPrice.ToFixedSizeBytes()
+	if err != nil {
+		return nil, err
+	}
+	gasLimit, err := tx.gasLimit.ToFixedSizeBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	parts := [][]byte{
+		tx.from.address,
+		tx.to.address,
+		value,
+		byteutils.FromUint64(tx.nonce),
+		byteutils.FromInt64(tx.timestamp),
+		[]byte(tx.data.Type),
+		tx.data.Payload,
+		{tx.txType},
+		gasPrice,
+		gasLimit,
+	}
+
+	return hash.Sha3256(parts...), nil
+}
+
+func (s *legacySigner) Sender(tx *Transaction) (*Address, error) {
+	if tx.chainID != s.chainID {
+		return nil, ErrInvalidChainID
+	}
+
+	wantHash, err := s.Hash(tx)
+	if err != nil {
+		return nil, err
+	}
+	if !tx.hash.Equals(wantHash) {
+		return nil, ErrInvalidTransactionHash
+	}
+
+	return RecoverSignerFromSignature(keystore.Algorithm(tx.alg), tx.hash, tx.sign)
+}
+
+func (s *legacySigner) SigParts(sig []byte) (r, sPart, v []byte, err error) {
+	if len(sig) != recoverableSigLength {
+		return nil, nil, nil, ErrInvalidSigParts
+	}
+	return sig[0:32], sig[32:64], sig[64:65], nil
+}
+
+// Sender recovers the address that signed tx under signer's rules. It is
+// the single canonical way the mempool, block validator and RPC layer
+// should recover a transaction's from-address, instead of each calling
+// into a Signer implementation directly.
+func Sender(signer Signer, tx *Transaction) (*Address, error) {
+	return signer.Sender(tx)
+}
@@ -0,0 +1,707 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/nebulasio/go-nebulas/core/pb"
+	"github.com/nebulasio/go-nebulas/core/state"
+	"github.com/nebulasio/go-nebulas/crypto/keystore"
+	"github.com/nebulasio/go-nebulas/util"
+	"github.com/nebulasio/go-nebulas/util/byteutils"
+)
+
+// Transaction payload types.
+const (
+	TxPayloadBinaryType     = "binary"
+	TxPayloadDeployType     = "deploy"
+	TxPayloadCallType       = "call"
+	TxPayloadAccessListType = "access_list"
+)
+
+// Transaction envelope types, carried on the wire as the TxType byte. Legacy
+// transactions (TxTypeLegacy) sign over nonce+value+data+gasPrice+gasLimit
+// exactly as before chainID binding existed, so signatures made before this
+// series stay valid; every later TxType additionally binds chainID into the
+// preimage, and TxTypeDynamicFee signs over maxFeePerGas/maxPriorityFeePerGas
+// instead of gasPrice.
+const (
+	TxTypeLegacy uint8 = iota
+	TxTypeDynamicFee
+)
+
+// Transaction execution event topics.
+const (
+	TopicExecuteTxSuccess = "chain.executeTxSuccess"
+	TopicExecuteTxFailed  = "chain.executeTxFailed"
+)
+
+// GasCountPerByte is the gas charged for each byte of payload data.
+const GasCountPerByte = 1
+
+// TransactionGasPrice is the default gas price used by transactions that
+// don't specify one explicitly.
+var TransactionGasPrice = util.NewUint128FromUint(1000000)
+
+// TransactionMaxGas is the default gasLimit used by transactions that
+// don't specify one explicitly.
+var TransactionMaxGas = util.NewUint128FromUint(60000000)
+
+// MinGasCountPerTransaction is the minimum amount of gas every transaction
+// consumes regardless of its payload.
+var MinGasCountPerTransaction, _ = util.NewUint128FromInt(20000)
+
+// MaxGasPrice and MaxGasLimit bound legacy gasPrice and every transaction's
+// gasLimit, matching the ranges in ErrInvalidGasPrice/ErrInvalidGasLimit.
+var (
+	MaxGasPrice = util.NewUint128FromUint(1000000000000)
+	MaxGasLimit = util.NewUint128FromUint(50000000000)
+)
+
+// Transaction represents a Nebulas transaction: a transfer of value and/or
+// a payload to execute, signed by its sender.
+type Transaction struct {
+	hash      byteutils.Hash
+	from      *Address
+	to        *Address
+	value     *util.Uint128
+	nonce     uint64
+	timestamp int64
+	data      *corepb.Data
+	chainID   uint32
+	gasPrice  *util.Uint128
+	gasLimit  *util.Uint128
+	alg       uint8
+	sign      byteutils.Hash
+
+	// txType selects the wire/signing envelope. See the TxType* constants.
+	txType uint8
+
+	// maxFeePerGas/maxPriorityFeePerGas replace gasPrice on TxTypeDynamicFee
+	// and TxTypeBlob transactions: the sender never pays more than
+	// maxFeePerGas per unit of gas, of which at most maxPriorityFeePerGas
+	// goes to the block's coinbase and the remainder (up to the block's
+	// base fee) is burned.
+	maxFeePerGas         *util.Uint128
+	maxPriorityFeePerGas *util.Uint128
+
+	// blobVersionedHashes/maxFeePerBlobGas are only set on TxTypeBlob
+	// transactions. See transaction_blob.go.
+	blobVersionedHashes []byteutils.Hash
+	maxFeePerBlobGas    *util.Uint128
+}
+
+// NewTransaction creates a new legacy (TxTypeLegacy) transaction.
+func NewTransaction(chainID uint32, from, to *Address, value *util.Uint128, nonce uint64, payloadType string, payload []byte, gasPrice, gasLimit *util.Uint128) *Transaction {
+	return &Transaction{
+		from:      from,
+		to:        to,
+		value:     value,
+		nonce:     nonce,
+		timestamp: time.Now().Unix(),
+		chainID:   chainID,
+		data:      &corepb.Data{Type: payloadType, Payload: payload},
+		gasPrice:  gasPrice,
+		gasLimit:  gasLimit,
+		txType:    TxTypeLegacy,
+	}
+}
+
+// NewDynamicFeeTransaction creates a new TxTypeDynamicFee transaction: it
+// pays at most maxFeePerGas per unit of gas, tipping the coinbase up to
+// maxPriorityFeePerGas and burning the rest.
+func NewDynamicFeeTransaction(chainID uint32, from, to *Address, value *util.Uint128, nonce uint64, payloadType string, payload []byte, gasLimit, maxFeePerGas, maxPriorityFeePerGas *util.Uint128) *Transaction {
+	return &Transaction{
+		from:                 from,
+		to:                   to,
+		value:                value,
+		nonce:                nonce,
+		timestamp:            time.Now().Unix(),
+		chainID:              chainID,
+		data:                 &corepb.Data{Type: payloadType, Payload: payload},
+		gasLimit:             gasLimit,
+		txType:               TxTypeDynamicFee,
+		maxFeePerGas:         maxFeePerGas,
+		maxPriorityFeePerGas: maxPriorityFeePerGas,
+	}
+}
+
+// Hash returns the transaction hash.
+func (tx *Transaction) Hash() byteutils.Hash { return tx.hash }
+
+// From returns the sender address.
+func (tx *Transaction) From() *Address { return tx.from }
+
+// To returns the recipient address.
+func (tx *Transaction) To() *Address { return tx.to }
+
+// Nonce returns the sender-scoped transaction sequence number.
+func (tx *Transaction) Nonce() uint64 { return tx.nonce }
+
+// ChainID returns the chain this transaction was signed for.
+func (tx *Transaction) ChainID() uint32 { return tx.chainID }
+
+// TxType returns the wire/signing envelope of this transaction.
+func (tx *Transaction) TxType() uint8 { return tx.txType }
+
+// GasPrice returns the legacy per-unit-of-gas price of the transaction. It
+// is meaningless for TxTypeDynamicFee transactions; use EffectiveGasPrice
+// instead.
+func (tx *Transaction) GasPrice() *util.Uint128 { return tx.gasPrice }
+
+// GasLimit returns the maximum amount of gas the transaction may consume.
+func (tx *Transaction) GasLimit() *util.Uint128 { return tx.gasLimit }
+
+// GasCountOfTxBase returns the gas a transaction consumes before its
+// payload is executed: the per-transaction base cost plus one unit of gas
+// per byte of payload data.
+func (tx *Transaction) GasCountOfTxBase() (*util.Uint128, error) {
+	txGas := MinGasCountPerTransaction
+	if tx.data == nil || len(tx.data.Payload) == 0 {
+		return txGas, nil
+	}
+
+	dataLengthGas, err := util.NewUint128FromInt(int64(len(tx.data.Payload)) * GasCountPerByte)
+	if err != nil {
+		return nil, err
+	}
+	return txGas.Add(dataLengthGas)
+}
+
+// EffectiveGasPrice returns the price per unit of gas the sender actually
+// pays when this transaction is executed on top of block. For legacy
+// transactions this is simply gasPrice; for TxTypeDynamicFee transactions
+// it is min(maxFeePerGas, baseFee+maxPriorityFeePerGas).
+func (tx *Transaction) EffectiveGasPrice(block *Block) (*util.Uint128, error) {
+	if tx.txType == TxTypeLegacy {
+		return tx.gasPrice, nil
+	}
+
+	baseFee := block.BaseFee()
+	tip, err := tx.priorityFeePerGas(baseFee)
+	if err != nil {
+		return nil, err
+	}
+	return baseFee.Add(tip)
+}
+
+// priorityFeePerGas returns the portion of EffectiveGasPrice that is routed
+// to the coinbase: min(maxPriorityFeePerGas, maxFeePerGas-baseFee).
+func (tx *Transaction) priorityFeePerGas(baseFee *util.Uint128) (*util.Uint128, error) {
+	if tx.maxFeePerGas.Cmp(baseFee) < 0 {
+		return nil, ErrFeeCapLessThanBaseFee
+	}
+	headroom, err := tx.maxFeePerGas.Sub(baseFee)
+	if err != nil {
+		return nil, err
+	}
+	if tx.maxPriorityFeePerGas.Cmp(headroom) < 0 {
+		return tx.maxPriorityFeePerGas, nil
+	}
+	return headroom, nil
+}
+
+// ToProto converts the transaction into its wire representation.
+func (tx *Transaction) ToProto() (proto.Message, error) {
+	value, err := tx.value.ToFixedSizeBytes()
+	if err != nil {
+		return nil, err
+	}
+	gasLimit, err := tx.gasLimit.ToFixedSizeBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &corepb.Transaction{
+		Hash:      tx.hash,
+		From:      tx.from.address,
+		To:        tx.to.address,
+		Value:     value,
+		Nonce:     tx.nonce,
+		Timestamp: tx.timestamp,
+		Data:      tx.data,
+		ChainId:   tx.chainID,
+		GasLimit:  gasLimit,
+		Alg:       uint32(tx.alg),
+		Sign:      tx.sign,
+		TxType:    uint32(tx.txType),
+	}
+
+	if tx.txType != TxTypeLegacy {
+		maxFeePerGas, err := tx.maxFeePerGas.ToFixedSizeBytes()
+		if err != nil {
+			return nil, err
+		}
+		maxPriorityFeePerGas, err := tx.maxPriorityFeePerGas.ToFixedSizeBytes()
+		if err != nil {
+			return nil, err
+		}
+		msg.MaxFeePerGas = maxFeePerGas
+		msg.MaxPriorityFeePerGas = maxPriorityFeePerGas
+	} else {
+		gasPrice, err := tx.gasPrice.ToFixedSizeBytes()
+		if err != nil {
+			return nil, err
+		}
+		msg.GasPrice = gasPrice
+	}
+
+	if tx.txType == TxTypeBlob {
+		maxFeePerBlobGas, err := tx.maxFeePerBlobGas.ToFixedSizeBytes()
+		if err != nil {
+			return nil, err
+		}
+		hashes := make([][]byte, len(tx.blobVersionedHashes))
+		for i, h := range tx.blobVersionedHashes {
+			hashes[i] = h
+		}
+		msg.MaxFeePerBlobGas = maxFeePerBlobGas
+		msg.BlobVersionedHashes = hashes
+	}
+
+	return msg, nil
+}
+
+// FromProto populates the transaction from its wire representation.
+func (tx *Transaction) FromProto(msg proto.Message) error {
+	pbTx, ok := msg.(*corepb.Transaction)
+	if !ok {
+		return ErrInvalidTxPayloadType
+	}
+
+	value, err := util.NewUint128FromFixedSizeBytes(pbTx.Value)
+	if err != nil {
+		return err
+	}
+	gasLimit, err := util.NewUint128FromFixedSizeBytes(pbTx.GasLimit)
+	if err != nil {
+		return err
+	}
+
+	tx.hash = pbTx.Hash
+	tx.from = &Address{address: pbTx.From}
+	tx.to = &Address{address: pbTx.To}
+	tx.value = value
+	tx.nonce = pbTx.Nonce
+	tx.timestamp = pbTx.Timestamp
+	tx.data = pbTx.Data
+	tx.chainID = pbTx.ChainId
+	tx.gasLimit = gasLimit
+	tx.alg = uint8(pbTx.Alg)
+	tx.sign = pbTx.Sign
+	tx.txType = uint8(pbTx.TxType)
+
+	if tx.txType != TxTypeLegacy {
+		maxFeePerGas, err := util.NewUint128FromFixedSizeBytes(pbTx.MaxFeePerGas)
+		if err != nil {
+			return err
+		}
+		maxPriorityFeePerGas, err := util.NewUint128FromFixedSizeBytes(pbTx.MaxPriorityFeePerGas)
+		if err != nil {
+			return err
+		}
+		tx.maxFeePerGas = maxFeePerGas
+		tx.maxPriorityFeePerGas = maxPriorityFeePerGas
+	} else {
+		gasPrice, err := util.NewUint128FromFixedSizeBytes(pbTx.GasPrice)
+		if err != nil {
+			return err
+		}
+		tx.gasPrice = gasPrice
+	}
+
+	if tx.txType == TxTypeBlob {
+		maxFeePerBlobGas, err := util.NewUint128FromFixedSizeBytes(pbTx.MaxFeePerBlobGas)
+		if err != nil {
+			return err
+		}
+		hashes := make([]byteutils.Hash, len(pbTx.BlobVersionedHashes))
+		for i, h := range pbTx.BlobVersionedHashes {
+			hashes[i] = h
+		}
+		tx.maxFeePerBlobGas = maxFeePerBlobGas
+		tx.blobVersionedHashes = hashes
+	}
+
+	return nil
+}
+
+// HashTransaction returns the canonical hash of tx's content, i.e. the
+// preimage that gets signed. The preimage itself is TxType-specific and
+// lives on the matching Signer's Hash method (legacySigner or
+// chainIDSigner, in signer.go); this is a convenience wrapper for callers,
+// like Sign, that just want tx's hash without picking a Signer themselves.
+func HashTransaction(tx *Transaction) (byteutils.Hash, error) {
+	return LatestSignerForChainID(tx.chainID).Hash(tx)
+}
+
+// Sign signs the transaction with signature, computing its hash and
+// signature bytes.
+func (tx *Transaction) Sign(signature keystore.Signature) error {
+	txHash, err := HashTransaction(tx)
+	if err != nil {
+		return err
+	}
+	sign, err := signature.Sign(txHash)
+	if err != nil {
+		return err
+	}
+	tx.hash = txHash
+	tx.alg = uint8(signature.Algorithm())
+	tx.sign = sign
+	return nil
+}
+
+// VerifyIntegrity verifies that the transaction was signed for chainID and
+// that its hash and signature match its content and sender, routing the
+// chainID check and signature recovery through the Signer LatestSignerForChainID
+// returns for chainID so replay protection and hash rules live in one
+// place instead of being duplicated here.
+func (tx *Transaction) VerifyIntegrity(chainID uint32) error {
+	if tx.gasLimit.Cmp(util.NewUint128()) == 0 {
+		return ErrZeroGasLimit
+	}
+	if tx.gasLimit.Cmp(MaxGasLimit) > 0 {
+		return ErrInvalidGasLimit
+	}
+
+	if tx.txType == TxTypeLegacy {
+		if tx.gasPrice.Cmp(util.NewUint128()) == 0 {
+			return ErrZeroGasPrice
+		}
+		if tx.gasPrice.Cmp(MaxGasPrice) > 0 {
+			return ErrInvalidGasPrice
+		}
+	} else {
+		if tx.maxPriorityFeePerGas.Cmp(tx.maxFeePerGas) > 0 {
+			return ErrTipAboveFeeCap
+		}
+	}
+
+	if tx.txType == TxTypeBlob {
+		if err := tx.verifyBlobVersionedHashes(); err != nil {
+			return err
+		}
+	}
+
+	signer := LatestSignerForChainID(chainID)
+	sender, err := Sender(signer, tx)
+	if err != nil {
+		return err
+	}
+	if !tx.from.Equals(sender) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyExecution executes the transaction against block, applying its
+// balance, gas and event side-effects, and records a
+// TopicExecuteTxSuccess/TopicExecuteTxFailed event against its hash.
+//
+// Only validation failures that make the transaction infeasible to charge
+// for at all (insufficient balance to cover its worst-case cost, a fee cap
+// below the block's base fee or blob base fee, or a gasLimit too small to
+// cover its base cost) are returned as an error; once
+// gas has been charged, any failure of the payload itself (a bad payload, a
+// reverted contract call, a failed value transfer) is recorded as a
+// TopicExecuteTxFailed event instead, since the transaction is still valid
+// and its gas fee has already been collected.
+func (tx *Transaction) VerifyExecution(block *Block) (*util.Uint128, error) {
+	gasUsed, _, err := tx.execute(block, false)
+	return gasUsed, err
+}
+
+// LocalExecution dry-runs the transaction against block without mutating
+// any account balances: it does not check that the sender can afford the
+// transaction, and it charges and transfers nothing. Unlike
+// VerifyExecution, it surfaces the payload's own execution error directly,
+// so callers (wallets, RPC eth_call-style queries, EstimateGas) can tell
+// why the transaction would fail.
+func (tx *Transaction) LocalExecution(block *Block) (*util.Uint128, string, error) {
+	block.begin()
+	defer block.rollback()
+
+	return tx.execute(block, true)
+}
+
+// ObservedAccessList dry-runs tx against block exactly like LocalExecution,
+// additionally reporting the contract addresses execution observed being
+// touched. It only ever reports tx.to, and only for TxPayloadCallType and
+// TxPayloadAccessListType, since this tree has no NVM to observe anything
+// a contract call does beyond reaching the account it targets.
+//
+// A wallet can call this once against the call it intends to make, then
+// resubmit the same call as a TxPayloadAccessListType transaction carrying
+// the returned access list, to get the discounted per-access gas rate for
+// everything it already knew it would touch.
+func (tx *Transaction) ObservedAccessList(block *Block) (*util.Uint128, []AccessTuple, error) {
+	gasUsed, _, err := tx.LocalExecution(block)
+	if tx.data.Type != TxPayloadCallType && tx.data.Type != TxPayloadAccessListType {
+		return gasUsed, nil, err
+	}
+	return gasUsed, []AccessTuple{{Address: tx.to.String()}}, err
+}
+
+// EstimateGas returns the minimum gasLimit under which tx would succeed
+// against block, so callers don't have to guess one before submitting.
+//
+// It probes tx.LocalExecution at hi := min(tx.gasLimit, block.GasLimit())
+// first; if that fails, no gasLimit will do, and the failure is returned
+// verbatim. Otherwise it binary-searches down from hi toward lo :=
+// GasCountOfTxBase(), narrowing hi on success and lo on ErrOutOfGasLimit/
+// ErrInsufficientBalance (signals that mean "try more gas") until hi-lo <=
+// 1. Any other error during the search is deterministic - more gas would
+// not change it - and is returned immediately instead of narrowing lo.
+// lo itself is never probed during the search (it's only ever a floor the
+// loop narrows toward), so once the loop has narrowed down to hi == lo+1
+// it probes lo one last time: if lo already succeeds - true of any
+// transaction whose payload needs no gas beyond GasCountOfTxBase, like a
+// plain transfer - it, not hi, is the minimum and is returned instead.
+//
+// Each probe mutates tx.gasLimit for the duration of the call; it is
+// restored before EstimateGas returns.
+func (tx *Transaction) EstimateGas(block *Block) (*util.Uint128, error) {
+	lo, err := tx.GasCountOfTxBase()
+	if err != nil {
+		return nil, err
+	}
+
+	hi := tx.gasLimit
+	if hi == nil || hi.Cmp(util.NewUint128()) == 0 {
+		hi = TransactionMaxGas
+	}
+	if blockLimit := block.GasLimit(); blockLimit != nil && blockLimit.Cmp(hi) < 0 {
+		hi = blockLimit
+	}
+
+	originalGasLimit := tx.gasLimit
+	defer func() { tx.gasLimit = originalGasLimit }()
+
+	probe := func(gasLimit *util.Uint128) error {
+		tx.gasLimit = gasLimit
+		_, _, err := tx.LocalExecution(block)
+		return err
+	}
+
+	if err := probe(hi); err != nil {
+		return nil, err
+	}
+
+	for {
+		span, err := hi.Sub(lo)
+		if err != nil {
+			return nil, err
+		}
+		if span.Cmp(mustUint128(1)) <= 0 {
+			if span.Cmp(util.NewUint128()) != 0 && probe(lo) == nil {
+				return lo, nil
+			}
+			return hi, nil
+		}
+
+		sum, err := lo.Add(hi)
+		if err != nil {
+			return nil, err
+		}
+		mid, err := sum.Div(mustUint128(2))
+		if err != nil {
+			return nil, err
+		}
+
+		switch probeErr := probe(mid); probeErr {
+		case nil:
+			hi = mid
+		case ErrOutOfGasLimit, ErrInsufficientBalance:
+			lo = mid
+		default:
+			return nil, probeErr
+		}
+	}
+}
+
+// execute charges the transaction's gas fee and runs its payload against
+// block's account state. Without dryRun (VerifyExecution), payload-level
+// failures are swallowed into a TopicExecuteTxFailed event. With dryRun
+// (LocalExecution), the sender's balance is not checked against the
+// transaction's worst-case cost, no balance is moved, the block's gas
+// counters are not updated and no event is recorded: execute returns right
+// after the payload has run, with its gas, result and own error.
+func (tx *Transaction) execute(block *Block, dryRun bool) (*util.Uint128, string, error) {
+	baseGas, err := tx.GasCountOfTxBase()
+	if err != nil {
+		return nil, "", err
+	}
+	if tx.gasLimit.Cmp(baseGas) < 0 {
+		return util.NewUint128(), "", ErrOutOfGasLimit
+	}
+
+	gasPrice, err := tx.EffectiveGasPrice(block)
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+
+	fromAcc, err := block.worldState.GetOrCreateUserAccount(tx.from.address)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var blobFee *util.Uint128
+	if tx.txType == TxTypeBlob {
+		blobFee, err = tx.blobGasFee(block)
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		blobFee = util.NewUint128()
+	}
+
+	// Check against gasLimit, not baseGas: a payload (e.g. AccessListPayload)
+	// can spend up to the rest of gasLimit on top of the base cost, and a
+	// sender who can't cover that worst case shouldn't get to start
+	// executing at all.
+	maxGasFee, err := gasPrice.Mul(tx.gasLimit)
+	if err != nil {
+		return nil, "", err
+	}
+	minCost, err := maxGasFee.Add(blobFee)
+	if err != nil {
+		return nil, "", err
+	}
+	if !dryRun && fromAcc.Balance().Cmp(minCost) < 0 {
+		return util.NewUint128(), "", ErrInsufficientBalance
+	}
+
+	if tx.txType == TxTypeBlob && !dryRun {
+		if err := fromAcc.SubBalance(blobFee); err != nil {
+			return nil, "", err
+		}
+		if err := block.addBlobGasUsed(tx.blobGasUsed()); err != nil {
+			return nil, "", err
+		}
+	}
+
+	payload, payloadErr := LoadPayload(tx.data)
+
+	gasUsed := baseGas
+	result := ""
+	var execErr error
+	if payloadErr != nil {
+		execErr = payloadErr
+	} else {
+		limitedGas, err := tx.gasLimit.Sub(baseGas)
+		if err != nil {
+			return nil, "", err
+		}
+		spentGas, payloadResult, err := payload.Execute(limitedGas, tx, block)
+		result = payloadResult
+		execErr = err
+		if spentGas != nil {
+			gasUsed, err = gasUsed.Add(spentGas)
+			if err != nil {
+				return nil, "", err
+			}
+			if gasUsed.Cmp(tx.gasLimit) > 0 {
+				gasUsed = tx.gasLimit
+			}
+		}
+	}
+
+	if dryRun {
+		return gasUsed, result, execErr
+	}
+
+	gasFee, err := gasPrice.Mul(gasUsed)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := fromAcc.SubBalance(gasFee); err != nil {
+		return nil, "", err
+	}
+	if err := tx.settleFees(block, gasUsed, gasPrice); err != nil {
+		return nil, "", err
+	}
+	if err := block.addGasUsed(gasUsed); err != nil {
+		return nil, "", err
+	}
+
+	if execErr == nil {
+		if err := tx.transferValue(block, fromAcc); err != nil {
+			execErr = err
+		}
+	}
+
+	topic := TopicExecuteTxSuccess
+	if execErr != nil {
+		topic = TopicExecuteTxFailed
+	}
+	block.worldState.RecordEvent(tx.hash, &state.Event{Topic: topic})
+
+	return gasUsed, result, nil
+}
+
+// transferValue moves tx.value from fromAcc to the recipient account.
+func (tx *Transaction) transferValue(block *Block, fromAcc state.Account) error {
+	if tx.value.Cmp(util.NewUint128()) == 0 {
+		return nil
+	}
+	if fromAcc.Balance().Cmp(tx.value) < 0 {
+		return ErrInsufficientBalance
+	}
+	toAcc, err := block.worldState.GetOrCreateUserAccount(tx.to.address)
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.SubBalance(tx.value); err != nil {
+		return err
+	}
+	return toAcc.AddBalance(tx.value)
+}
+
+// settleFees credits the gas fee charged to the sender to the block's
+// coinbase. Legacy transactions route their entire fee to the coinbase;
+// TxTypeDynamicFee and TxTypeBlob transactions route only the priority fee
+// (the tip) to the coinbase and burn the base fee portion by simply never
+// crediting it.
+func (tx *Transaction) settleFees(block *Block, gasUsed, gasPrice *util.Uint128) error {
+	coinbaseAcc, err := block.worldState.GetOrCreateUserAccount(block.header.coinbase.address)
+	if err != nil {
+		return err
+	}
+
+	if tx.txType == TxTypeLegacy {
+		fee, err := gasPrice.Mul(gasUsed)
+		if err != nil {
+			return err
+		}
+		return coinbaseAcc.AddBalance(fee)
+	}
+
+	tip, err := tx.priorityFeePerGas(block.BaseFee())
+	if err != nil {
+		return err
+	}
+	tipFee, err := tip.Mul(gasUsed)
+	if err != nil {
+		return err
+	}
+	return coinbaseAcc.AddBalance(tipFee)
+}
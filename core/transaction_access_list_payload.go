@@ -0,0 +1,123 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+
+	"github.com/nebulasio/go-nebulas/core/state"
+	"github.com/nebulasio/go-nebulas/util"
+)
+
+// GasCountPerDeclaredAccess is charged for each access in an
+// AccessListPayload's own AccessList that the call actually touches: the
+// discount a wallet earns for pre-warming the working set.
+const GasCountPerDeclaredAccess = 100
+
+// GasCountPerUndeclaredAccess is charged for touching a contract the
+// payload's AccessList did not declare. It is 26x GasCountPerDeclaredAccess
+// so that declaring every address a call will actually touch is always
+// worth doing, never a wash - matching the relative (not absolute) cost
+// EIP-2930 charges a cold versus warm account access.
+const GasCountPerUndeclaredAccess = 2600
+
+// AccessTuple names a contract address and the storage keys within it
+// that a transaction promises to touch. StorageKeys is carried for wire
+// compatibility with a future NVM that can charge per declared key; this
+// tree only ever charges per declared address.
+type AccessTuple struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
+}
+
+// AccessListPayload invokes a function exported by a previously deployed
+// contract, exactly like CallPayload, but additionally declares the
+// contract addresses (and, eventually, storage keys) the call promises to
+// touch. Because AccessList is serialized as part of the payload bytes,
+// and the payload bytes are part of HashTransaction's signed preimage, a
+// tampered access list invalidates the transaction's signature like any
+// other payload change.
+type AccessListPayload struct {
+	Function   string        `json:"function"`
+	Args       string        `json:"args"`
+	AccessList []AccessTuple `json:"accessList"`
+}
+
+// NewAccessListPayload creates a new AccessListPayload.
+func NewAccessListPayload(function, args string, accessList []AccessTuple) *AccessListPayload {
+	return &AccessListPayload{
+		Function:   function,
+		Args:       args,
+		AccessList: accessList,
+	}
+}
+
+// LoadAccessListPayload deserializes an AccessListPayload from bytes.
+func LoadAccessListPayload(bytes []byte) (*AccessListPayload, error) {
+	payload := &AccessListPayload{}
+	if err := json.Unmarshal(bytes, payload); err != nil {
+		return nil, ErrInvalidTxPayloadType
+	}
+	if payload.Function == "" {
+		return nil, ErrInvalidTxPayloadType
+	}
+	for _, tuple := range payload.AccessList {
+		if _, err := AddressParse(tuple.Address); err != nil {
+			return nil, ErrInvalidAccessList
+		}
+	}
+	return payload, nil
+}
+
+// ToBytes serializes the payload.
+func (payload *AccessListPayload) ToBytes() ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// declaresAddress reports whether addr appears in the payload's own
+// access list.
+func (payload *AccessListPayload) declaresAddress(addr *Address) bool {
+	if addr == nil {
+		return false
+	}
+	for _, tuple := range payload.AccessList {
+		if tuple.Address == addr.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Execute looks up tx.to the same way CallPayload does: this tree doesn't
+// vendor the NVM, so no contract can actually be invoked and the call
+// always fails with state.ErrAccountNotFound. What AccessListPayload adds
+// is the gas charged for that lookup - GasCountPerDeclaredAccess if tx.to
+// is in the payload's own access list, GasCountPerUndeclaredAccess
+// otherwise - so declaring the account a call already knows it will touch
+// is strictly cheaper than not declaring it.
+func (payload *AccessListPayload) Execute(limitedGas *util.Uint128, tx *Transaction, block *Block) (*util.Uint128, string, error) {
+	gasUsed := util.NewUint128FromUint(GasCountPerUndeclaredAccess)
+	if payload.declaresAddress(tx.to) {
+		gasUsed = util.NewUint128FromUint(GasCountPerDeclaredAccess)
+	}
+	if gasUsed.Cmp(limitedGas) > 0 {
+		return limitedGas, "", ErrOutOfGasLimit
+	}
+	return gasUsed, "", state.ErrAccountNotFound
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import "github.com/nebulasio/go-nebulas/util"
+
+// BinaryPayload carries an opaque memo alongside a plain value transfer. It
+// has no execution semantics of its own beyond the base gas every
+// transaction already pays for its payload bytes.
+type BinaryPayload struct {
+	data []byte
+}
+
+// NewBinaryPayload creates a new BinaryPayload wrapping data.
+func NewBinaryPayload(data []byte) *BinaryPayload {
+	return &BinaryPayload{data: data}
+}
+
+// LoadBinaryPayload deserializes a BinaryPayload from bytes.
+func LoadBinaryPayload(bytes []byte) (*BinaryPayload, error) {
+	return NewBinaryPayload(bytes), nil
+}
+
+// ToBytes serializes the payload.
+func (payload *BinaryPayload) ToBytes() ([]byte, error) {
+	return payload.data, nil
+}
+
+// Execute is a no-op: BinaryPayload has nothing to run beyond the value
+// transfer that Transaction.execute already performs for every payload
+// type.
+func (payload *BinaryPayload) Execute(limitedGas *util.Uint128, tx *Transaction, block *Block) (*util.Uint128, string, error) {
+	return util.NewUint128(), "", nil
+}
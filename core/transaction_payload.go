@@ -0,0 +1,55 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/nebulasio/go-nebulas/core/pb"
+	"github.com/nebulasio/go-nebulas/util"
+)
+
+// TxPayload is executed once a transaction's base gas and feasibility
+// checks have passed. Implementations charge additional gas (on top of
+// GasCountOfTxBase) for whatever work their payload type performs.
+type TxPayload interface {
+	// ToBytes serializes the payload for storage in a Transaction's data.
+	ToBytes() ([]byte, error)
+
+	// Execute runs the payload against block using at most limitedGas
+	// gas, returning the gas actually spent, a string result (used by
+	// contract call/deploy payloads), and an error if the payload itself
+	// failed.
+	Execute(limitedGas *util.Uint128, tx *Transaction, block *Block) (*util.Uint128, string, error)
+}
+
+// LoadPayload deserializes the payload carried by data, dispatching on its
+// declared type.
+func LoadPayload(data *corepb.Data) (TxPayload, error) {
+	switch data.Type {
+	case TxPayloadBinaryType:
+		return LoadBinaryPayload(data.Payload)
+	case TxPayloadDeployType:
+		return LoadDeployPayload(data.Payload)
+	case TxPayloadCallType:
+		return LoadCallPayload(data.Payload)
+	case TxPayloadAccessListType:
+		return LoadAccessListPayload(data.Payload)
+	default:
+		return nil, ErrInvalidTxPayloadType
+	}
+}
@@ -60,6 +60,37 @@ func mockTransaction(chainID uint32, nonce uint64, payloadType string, payload [
 	return tx
 }
 
+func mockDynamicFeeTransaction(chainID uint32, nonce uint64, maxFeePerGas, maxPriorityFeePerGas *util.Uint128) *Transaction {
+	from := mockAddress()
+	to := mockAddress()
+	return NewDynamicFeeTransaction(chainID, from, to, util.NewUint128(), nonce, TxPayloadBinaryType, nil, TransactionMaxGas, maxFeePerGas, maxPriorityFeePerGas)
+}
+
+func mockBlobVersionedHash(seed byte) byteutils.Hash {
+	return VersionedHashForCommitment([]byte{seed})
+}
+
+func mockBlobTransaction(chainID uint32, nonce uint64, maxFeePerGas, maxPriorityFeePerGas, maxFeePerBlobGas *util.Uint128, blobVersionedHashes []byteutils.Hash) *Transaction {
+	from := mockAddress()
+	to := mockAddress()
+	return NewBlobTransaction(chainID, from, to, util.NewUint128(), nonce, TxPayloadBinaryType, nil, TransactionMaxGas, maxFeePerGas, maxPriorityFeePerGas, maxFeePerBlobGas, blobVersionedHashes)
+}
+
+func mockAccessListTransaction(chainID uint32, nonce uint64, to *Address, function, args string, accessList []AccessTuple) *Transaction {
+	from := mockAddress()
+	payload, _ := NewAccessListPayload(function, args, accessList).ToBytes()
+	return NewTransaction(chainID, from, to, util.NewUint128(), nonce, TxPayloadAccessListType, payload, TransactionGasPrice, TransactionMaxGas)
+}
+
+func signTransaction(t *testing.T, tx *Transaction) {
+	key, err := keystore.DefaultKS.GetUnlocked(tx.from.String())
+	assert.Nil(t, err)
+	signature, err := crypto.NewSignature(keystore.SECP256K1)
+	assert.Nil(t, err)
+	assert.Nil(t, signature.InitSign(key.(keystore.PrivateKey)))
+	assert.Nil(t, tx.Sign(signature))
+}
+
 func TestTransaction(t *testing.T) {
 	type fields struct {
 		hash      byteutils.Hash
@@ -164,6 +195,78 @@ func TestTransaction_VerifyIntegrity(t *testing.T) {
 			})
 		}
 	}
+
+	// A transaction signed for one chain must not verify on another: the
+	// signer LatestSignerForChainID returns for the wrong chainID must
+	// reject it before signature recovery even runs, for every TxType.
+	t.Run("cross-chain replay is rejected", func(t *testing.T) {
+		legacyTx := NewTransaction(1, mockAddress(), mockAddress(), util.NewUint128(), 10, TxPayloadBinaryType, []byte("datadata"), TransactionGasPrice, TransactionMaxGas)
+		signTransaction(t, legacyTx)
+		assert.Nil(t, legacyTx.VerifyIntegrity(1))
+		assert.Equal(t, ErrInvalidChainID, legacyTx.VerifyIntegrity(2))
+
+		dynamicTx := mockDynamicFeeTransaction(1, 0, TransactionGasPrice, util.NewUint128())
+		signTransaction(t, dynamicTx)
+		assert.Nil(t, dynamicTx.VerifyIntegrity(1))
+		assert.Equal(t, ErrInvalidChainID, dynamicTx.VerifyIntegrity(2))
+
+		hashes := []byteutils.Hash{mockBlobVersionedHash(1)}
+		blobTx := mockBlobTransaction(1, 0, TransactionGasPrice, util.NewUint128(), util.NewUint128FromUint(1), hashes)
+		signTransaction(t, blobTx)
+		assert.Nil(t, blobTx.VerifyIntegrity(1))
+		assert.Equal(t, ErrInvalidChainID, blobTx.VerifyIntegrity(2))
+	})
+
+	// TxTypeLegacy's signed preimage predates chainID binding and must not
+	// include it, unlike every later TxType - this is what LatestSignerForChainID
+	// actually dispatching on TxType buys over always hashing the same way.
+	t.Run("legacy preimage omits chainID, typed preimages bind it", func(t *testing.T) {
+		legacyTx := NewTransaction(1, mockAddress(), mockAddress(), util.NewUint128(), 10, TxPayloadBinaryType, []byte("datadata"), TransactionGasPrice, TransactionMaxGas)
+		signTransaction(t, legacyTx)
+		legacyHash := legacyTx.hash
+		legacyTx.chainID = 2
+		rehashed, err := HashTransaction(legacyTx)
+		assert.Nil(t, err)
+		assert.Equal(t, legacyHash, rehashed)
+
+		dynamicTx := mockDynamicFeeTransaction(1, 0, TransactionGasPrice, util.NewUint128())
+		signTransaction(t, dynamicTx)
+		dynamicHash := dynamicTx.hash
+		dynamicTx.chainID = 2
+		rehashed, err = HashTransaction(dynamicTx)
+		assert.Nil(t, err)
+		assert.NotEqual(t, dynamicHash, rehashed)
+	})
+
+	t.Run("tip above fee cap is rejected", func(t *testing.T) {
+		maxFeePerGas, _ := util.NewUint128FromInt(1000000)
+		maxPriorityFeePerGas, _ := util.NewUint128FromInt(2000000)
+		tx := mockDynamicFeeTransaction(1, 0, maxFeePerGas, maxPriorityFeePerGas)
+		signTransaction(t, tx)
+		assert.Equal(t, ErrTipAboveFeeCap, tx.VerifyIntegrity(1))
+	})
+
+	t.Run("gasLimit and legacy gasPrice bounds are enforced", func(t *testing.T) {
+		zeroGasLimitTx := NewTransaction(1, mockAddress(), mockAddress(), util.NewUint128(), 10, TxPayloadBinaryType, nil, TransactionGasPrice, util.NewUint128())
+		signTransaction(t, zeroGasLimitTx)
+		assert.Equal(t, ErrZeroGasLimit, zeroGasLimitTx.VerifyIntegrity(1))
+
+		overGasLimit, err := MaxGasLimit.Add(util.NewUint128FromUint(1))
+		assert.Nil(t, err)
+		tooMuchGasLimitTx := NewTransaction(1, mockAddress(), mockAddress(), util.NewUint128(), 10, TxPayloadBinaryType, nil, TransactionGasPrice, overGasLimit)
+		signTransaction(t, tooMuchGasLimitTx)
+		assert.Equal(t, ErrInvalidGasLimit, tooMuchGasLimitTx.VerifyIntegrity(1))
+
+		zeroGasPriceTx := NewTransaction(1, mockAddress(), mockAddress(), util.NewUint128(), 10, TxPayloadBinaryType, nil, util.NewUint128(), TransactionMaxGas)
+		signTransaction(t, zeroGasPriceTx)
+		assert.Equal(t, ErrZeroGasPrice, zeroGasPriceTx.VerifyIntegrity(1))
+
+		overGasPrice, err := MaxGasPrice.Add(util.NewUint128FromUint(1))
+		assert.Nil(t, err)
+		tooHighGasPriceTx := NewTransaction(1, mockAddress(), mockAddress(), util.NewUint128(), 10, TxPayloadBinaryType, nil, overGasPrice, TransactionMaxGas)
+		signTransaction(t, tooHighGasPriceTx)
+		assert.Equal(t, ErrInvalidGasPrice, tooHighGasPriceTx.VerifyIntegrity(1))
+	})
 }
 
 func TestTransaction_VerifyExecution(t *testing.T) {
@@ -382,17 +485,17 @@ func TestTransaction_VerifyExecution(t *testing.T) {
 
 			block := bc.tailBlock
 			block.begin()
-			fromAcc, err := block.accState.GetOrCreateUserAccount(tt.tx.from.address)
+			fromAcc, err := block.worldState.GetOrCreateUserAccount(tt.tx.from.address)
 			assert.Nil(t, err)
 			fromAcc.AddBalance(tt.fromBalance)
 
 			gasUsed, executionErr := tt.tx.VerifyExecution(block)
 
-			fromAcc, err = block.accState.GetOrCreateUserAccount(tt.tx.from.address)
+			fromAcc, err = block.worldState.GetOrCreateUserAccount(tt.tx.from.address)
 			assert.Nil(t, err)
-			toAcc, err := block.accState.GetOrCreateUserAccount(tt.tx.to.address)
+			toAcc, err := block.worldState.GetOrCreateUserAccount(tt.tx.to.address)
 			assert.Nil(t, err)
-			coinbaseAcc, err := block.accState.GetOrCreateUserAccount(block.header.coinbase.address)
+			coinbaseAcc, err := block.worldState.GetOrCreateUserAccount(block.header.coinbase.address)
 			assert.Nil(t, err)
 			if tt.gasUsed != nil {
 				assert.Equal(t, tt.gasUsed, gasUsed)
@@ -472,15 +575,15 @@ func TestTransaction_LocalExecution(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			fromAcc, err := block.accState.GetOrCreateUserAccount(tt.tx.from.address)
+			fromAcc, err := block.worldState.GetOrCreateUserAccount(tt.tx.from.address)
 			assert.Nil(t, err)
 			fromBefore := fromAcc.Balance()
 
-			toAcc, err := block.accState.GetOrCreateUserAccount(tt.tx.to.address)
+			toAcc, err := block.worldState.GetOrCreateUserAccount(tt.tx.to.address)
 			assert.Nil(t, err)
 			toBefore := toAcc.Balance()
 
-			coinbaseAcc, err := block.accState.GetOrCreateUserAccount(block.header.coinbase.address)
+			coinbaseAcc, err := block.worldState.GetOrCreateUserAccount(block.header.coinbase.address)
 			assert.Nil(t, err)
 			coinbaseBefore := coinbaseAcc.Balance()
 
@@ -490,17 +593,309 @@ func TestTransaction_LocalExecution(t *testing.T) {
 			assert.Equal(t, tt.result, result)
 			assert.Equal(t, tt.gasUsed, gasUsed)
 
-			fromAcc, err = block.accState.GetOrCreateUserAccount(tt.tx.from.address)
+			fromAcc, err = block.worldState.GetOrCreateUserAccount(tt.tx.from.address)
 			assert.Nil(t, err)
 			assert.Equal(t, fromBefore, fromAcc.Balance())
 
-			toAcc, err = block.accState.GetOrCreateUserAccount(tt.tx.to.address)
+			toAcc, err = block.worldState.GetOrCreateUserAccount(tt.tx.to.address)
 			assert.Nil(t, err)
 			assert.Equal(t, toBefore, toAcc.Balance())
 
-			coinbaseAcc, err = block.accState.GetOrCreateUserAccount(block.header.coinbase.address)
+			coinbaseAcc, err = block.worldState.GetOrCreateUserAccount(block.header.coinbase.address)
 			assert.Nil(t, err)
 			assert.Equal(t, coinbaseBefore, coinbaseAcc.Balance())
 		})
 	}
 }
+
+// TestTransaction_DynamicFeeExecution covers the TxTypeDynamicFee specific
+// parts of VerifyExecution: fee-cap-below-basefee rejection, priority-fee
+// capping, and the sender/coinbase/burn accounting split between the tip
+// (credited to the coinbase) and the base fee (burned).
+func TestTransaction_DynamicFeeExecution(t *testing.T) {
+	bc := testNeb(t).chain
+	block := bc.tailBlock
+	baseFee := block.BaseFee()
+
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+
+	t.Run("fee cap below base fee is rejected", func(t *testing.T) {
+		maxFeePerGas, _ := baseFee.Sub(util.NewUint128FromUint(1))
+		tx := mockDynamicFeeTransaction(bc.chainID, 0, maxFeePerGas, util.NewUint128())
+		signTransaction(t, tx)
+
+		block.begin()
+		fromAcc, err := block.worldState.GetOrCreateUserAccount(tx.from.address)
+		assert.Nil(t, err)
+		assert.Nil(t, fromAcc.AddBalance(balance))
+
+		gasUsed, execErr := tx.VerifyExecution(block)
+		assert.Equal(t, ErrFeeCapLessThanBaseFee, execErr)
+		assert.Equal(t, util.NewUint128(), gasUsed)
+
+		block.rollback()
+	})
+
+	t.Run("priority fee is capped at maxFeePerGas minus base fee", func(t *testing.T) {
+		headroom := util.NewUint128FromUint(50)
+		maxFeePerGas, _ := baseFee.Add(headroom)
+		wantTip := util.NewUint128FromUint(10000)
+		tx := mockDynamicFeeTransaction(bc.chainID, 0, maxFeePerGas, wantTip)
+		signTransaction(t, tx)
+
+		block.begin()
+		fromAcc, err := block.worldState.GetOrCreateUserAccount(tx.from.address)
+		assert.Nil(t, err)
+		assert.Nil(t, fromAcc.AddBalance(balance))
+
+		gasUsed, execErr := tx.VerifyExecution(block)
+		assert.Nil(t, execErr)
+
+		coinbaseAcc, err := block.worldState.GetOrCreateUserAccount(block.header.coinbase.address)
+		assert.Nil(t, err)
+		wantCoinbaseBalance, err := headroom.Mul(gasUsed)
+		assert.Nil(t, err)
+		assert.Equal(t, wantCoinbaseBalance.String(), coinbaseAcc.Balance().String())
+
+		block.rollback()
+	})
+
+	t.Run("sender pays burn plus tip, coinbase receives only the tip", func(t *testing.T) {
+		tip := util.NewUint128FromUint(100)
+		maxFeePerGas, _ := baseFee.Mul(util.NewUint128FromUint(2))
+		tx := mockDynamicFeeTransaction(bc.chainID, 0, maxFeePerGas, tip)
+		signTransaction(t, tx)
+
+		block.begin()
+		fromAcc, err := block.worldState.GetOrCreateUserAccount(tx.from.address)
+		assert.Nil(t, err)
+		assert.Nil(t, fromAcc.AddBalance(balance))
+
+		gasUsed, execErr := tx.VerifyExecution(block)
+		assert.Nil(t, execErr)
+
+		effectiveGasPrice, err := baseFee.Add(tip)
+		assert.Nil(t, err)
+		totalFee, err := effectiveGasPrice.Mul(gasUsed)
+		assert.Nil(t, err)
+		wantAfterBalance, err := balance.Sub(totalFee)
+		assert.Nil(t, err)
+
+		fromAcc, err = block.worldState.GetOrCreateUserAccount(tx.from.address)
+		assert.Nil(t, err)
+		assert.Equal(t, wantAfterBalance.String(), fromAcc.Balance().String())
+
+		coinbaseAcc, err := block.worldState.GetOrCreateUserAccount(block.header.coinbase.address)
+		assert.Nil(t, err)
+		wantCoinbaseBalance, err := tip.Mul(gasUsed)
+		assert.Nil(t, err)
+		assert.Equal(t, wantCoinbaseBalance.String(), coinbaseAcc.Balance().String())
+
+		block.rollback()
+	})
+}
+
+// TestTransaction_BlobExecution covers the TxTypeBlob specific parts of
+// VerifyIntegrity and VerifyExecution: malformed versioned hashes are
+// rejected before the signature is even checked, and a valid blob
+// transaction burns blobGasUsed*blobBaseFee from the sender on top of its
+// normal execution gas, crediting none of it to the coinbase.
+func TestTransaction_BlobExecution(t *testing.T) {
+	bc := testNeb(t).chain
+	block := bc.tailBlock
+	baseFee := block.BaseFee()
+
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+
+	t.Run("malformed blob versioned hash is rejected", func(t *testing.T) {
+		badHash := mockBlobVersionedHash(1)
+		badHash[0] = 0x02 // not BlobVersionedHashVersion
+		tx := mockBlobTransaction(bc.chainID, 0, baseFee, util.NewUint128(), util.NewUint128FromUint(1), []byteutils.Hash{badHash})
+		signTransaction(t, tx)
+
+		err := tx.VerifyIntegrity(tx.chainID)
+		assert.Equal(t, ErrInvalidBlobVersionedHash, err)
+	})
+
+	t.Run("no blob hashes is rejected", func(t *testing.T) {
+		tx := mockBlobTransaction(bc.chainID, 0, baseFee, util.NewUint128(), util.NewUint128FromUint(1), nil)
+		signTransaction(t, tx)
+
+		err := tx.VerifyIntegrity(tx.chainID)
+		assert.Equal(t, ErrNoBlobs, err)
+	})
+
+	t.Run("blob gas fee is burned from sender", func(t *testing.T) {
+		block.header.blobBaseFee = util.NewUint128FromUint(7)
+
+		hashes := []byteutils.Hash{mockBlobVersionedHash(1), mockBlobVersionedHash(2)}
+		tx := mockBlobTransaction(bc.chainID, 0, baseFee, util.NewUint128(), util.NewUint128FromUint(7), hashes)
+		signTransaction(t, tx)
+
+		block.begin()
+		fromAcc, err := block.worldState.GetOrCreateUserAccount(tx.from.address)
+		assert.Nil(t, err)
+		assert.Nil(t, fromAcc.AddBalance(balance))
+
+		gasUsed, execErr := tx.VerifyExecution(block)
+		assert.Nil(t, execErr)
+
+		blobFee, err := block.BlobBaseFee().Mul(tx.blobGasUsed())
+		assert.Nil(t, err)
+		execFee, err := baseFee.Mul(gasUsed)
+		assert.Nil(t, err)
+		totalFee, err := blobFee.Add(execFee)
+		assert.Nil(t, err)
+		wantAfterBalance, err := balance.Sub(totalFee)
+		assert.Nil(t, err)
+
+		fromAcc, err = block.worldState.GetOrCreateUserAccount(tx.from.address)
+		assert.Nil(t, err)
+		assert.Equal(t, wantAfterBalance.String(), fromAcc.Balance().String())
+
+		coinbaseAcc, err := block.worldState.GetOrCreateUserAccount(block.header.coinbase.address)
+		assert.Nil(t, err)
+		assert.Equal(t, util.NewUint128().String(), coinbaseAcc.Balance().String())
+
+		assert.Equal(t, uint64(2*BlobGasPerBlob), block.BlobGasUsed().Uint64())
+
+		block.rollback()
+		block.header.blobBaseFee = nil
+	})
+}
+
+// TestTransaction_EstimateGas covers EstimateGas's binary search: it must
+// converge on the same gasUsed values TestTransaction_LocalExecution
+// hardcodes for a normal transfer and a contract deploy, and must return a
+// deterministic payload error verbatim, without narrowing, for a call that
+// fails regardless of gas.
+func TestTransaction_EstimateGas(t *testing.T) {
+	type testCase struct {
+		name   string
+		tx     *Transaction
+		wanted *util.Uint128
+		err    error
+	}
+
+	tests := []testCase{}
+
+	bc := testNeb(t).chain
+
+	normalTx := mockNormalTransaction(bc.chainID, 0)
+	normalTx.value, _ = util.NewUint128FromInt(1000000)
+	tests = append(tests, testCase{
+		name:   "normal tx",
+		tx:     normalTx,
+		wanted: MinGasCountPerTransaction,
+	})
+
+	deployTx := mockDeployTransaction(bc.chainID, 0)
+	deployTx.value = util.NewUint128()
+	deployGasUsed, _ := util.NewUint128FromInt(21232)
+	tests = append(tests, testCase{
+		name:   "contract deploy tx",
+		tx:     deployTx,
+		wanted: deployGasUsed,
+	})
+
+	// a call fails with state.ErrAccountNotFound regardless of gasLimit, so
+	// EstimateGas must return it immediately instead of narrowing.
+	callTx := mockCallTransaction(bc.chainID, 1, "totalSupply", "")
+	callTx.value = util.NewUint128()
+	tests = append(tests, testCase{
+		name: "contract call tx reverts regardless of gas",
+		tx:   callTx,
+		err:  state.ErrAccountNotFound,
+	})
+
+	block := bc.tailBlock
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fromAcc, err := block.worldState.GetOrCreateUserAccount(tt.tx.from.address)
+			assert.Nil(t, err)
+			fromBefore := fromAcc.Balance()
+
+			gasLimitBefore := tt.tx.gasLimit
+
+			gasEstimate, err := tt.tx.EstimateGas(block)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wanted, gasEstimate)
+			assert.Equal(t, gasLimitBefore, tt.tx.gasLimit)
+
+			fromAcc, err = block.worldState.GetOrCreateUserAccount(tt.tx.from.address)
+			assert.Nil(t, err)
+			assert.Equal(t, fromBefore, fromAcc.Balance())
+		})
+	}
+}
+
+// TestTransaction_AccessListExecution covers the TxPayloadAccessListType
+// specific part of VerifyExecution: declaring the account a call touches
+// in its own access list must cost strictly less gas than not declaring
+// it, and ObservedAccessList must report that account so a wallet can
+// build the access list in the first place.
+func TestTransaction_AccessListExecution(t *testing.T) {
+	bc := testNeb(t).chain
+	block := bc.tailBlock
+
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+
+	fund := func(tx *Transaction) {
+		fromAcc, err := block.worldState.GetOrCreateUserAccount(tx.from.address)
+		assert.Nil(t, err)
+		assert.Nil(t, fromAcc.AddBalance(balance))
+	}
+
+	t.Run("declared access costs less gas than undeclared", func(t *testing.T) {
+		to := mockAddress()
+
+		declaredTx := mockAccessListTransaction(bc.chainID, 0, to, "totalSupply", "", []AccessTuple{{Address: to.String()}})
+		block.begin()
+		fund(declaredTx)
+		declaredGasUsed, execErr := declaredTx.VerifyExecution(block)
+		assert.Nil(t, execErr)
+		block.rollback()
+
+		undeclaredTx := mockAccessListTransaction(bc.chainID, 0, to, "totalSupply", "", nil)
+		block.begin()
+		fund(undeclaredTx)
+		undeclaredGasUsed, execErr := undeclaredTx.VerifyExecution(block)
+		assert.Nil(t, execErr)
+		block.rollback()
+
+		assert.Equal(t, -1, declaredGasUsed.Cmp(undeclaredGasUsed))
+	})
+
+	t.Run("balance covering base gas but not worst-case payload gas is rejected upfront", func(t *testing.T) {
+		to := mockAddress()
+		tx := mockAccessListTransaction(bc.chainID, 0, to, "totalSupply", "", nil)
+
+		baseGas, err := tx.GasCountOfTxBase()
+		assert.Nil(t, err)
+		tx.gasLimit, err = baseGas.Add(util.NewUint128FromUint(GasCountPerUndeclaredAccess))
+		assert.Nil(t, err)
+
+		baseGasFee, err := TransactionGasPrice.Mul(baseGas)
+		assert.Nil(t, err)
+
+		block.begin()
+		fromAcc, err := block.worldState.GetOrCreateUserAccount(tx.from.address)
+		assert.Nil(t, err)
+		assert.Nil(t, fromAcc.AddBalance(baseGasFee))
+
+		gasUsed, execErr := tx.VerifyExecution(block)
+		assert.Equal(t, ErrInsufficientBalance, execErr)
+		assert.Equal(t, util.NewUint128(), gasUsed)
+		block.rollback()
+	})
+
+	t.Run("observed access list reports the touched account", func(t *testing.T) {
+		callTx := mockCallTransaction(bc.chainID, 0, "totalSupply", "")
+
+		_, accessList, err := callTx.ObservedAccessList(block)
+		assert.Equal(t, state.ErrAccountNotFound, err)
+		assert.Equal(t, []AccessTuple{{Address: callTx.to.String()}}, accessList)
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nebulasio/go-nebulas/core/state"
+	"github.com/nebulasio/go-nebulas/crypto/keystore"
+	"github.com/nebulasio/go-nebulas/storage"
+)
+
+// testChainID is the chain used by every test in this package.
+const testChainID = 100
+
+// mockAddress creates a brand new account in the default keystore and
+// returns its address, ready to sign transactions with.
+func mockAddress() *Address {
+	ks := keystore.DefaultKS
+	priv := keystore.NewSECP256K1PrivateKey()
+	pubdata, _ := priv.PublicKey().Encoded()
+	addr, _ := NewAddressFromPublicKey(pubdata)
+	ks.SetKey(addr.String(), priv, []byte("passphrase"))
+	ks.Unlock(addr.String(), []byte("passphrase"), 365*24*time.Hour)
+	return addr
+}
+
+// BlockChain is a minimal, single-block stand-in for the real chain used
+// only to exercise Transaction's execution paths in tests.
+type BlockChain struct {
+	chainID   uint32
+	tailBlock *Block
+}
+
+// neb bundles together the state needed to exercise a transaction
+// against a block, mirroring the real node's top-level Neblet type closely
+// enough for this package's tests.
+type neb struct {
+	chain *BlockChain
+}
+
+// testNeb builds a fresh, single-block in-memory chain for use in a test.
+func testNeb(t *testing.T) *neb {
+	stor, err := storage.NewMemoryStorage()
+	if err != nil {
+		t.Fatalf("failed to create storage: %s", err)
+	}
+	// Consensus is only consulted for dynasty-related state this package's
+	// tests never touch, so a nil Consensus is enough to exercise
+	// WorldState's account/event/gas bookkeeping.
+	worldState, err := state.NewWorldState(nil, stor)
+	if err != nil {
+		t.Fatalf("failed to create world state: %s", err)
+	}
+
+	block := &Block{
+		header: &BlockHeader{
+			coinbase: mockAddress(),
+			chainID:  testChainID,
+			gasLimit: TransactionMaxGas,
+			baseFee:  mustUint128(InitialBaseFee),
+		},
+		worldState: worldState,
+	}
+
+	return &neb{
+		chain: &BlockChain{
+			chainID:   testChainID,
+			tailBlock: block,
+		},
+	}
+}